@@ -0,0 +1,250 @@
+// This command demonstrates how to manage Azure virtual machines using the
+// pkg/azure driver package.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/seyadava/virtual-machines-go-manage/pkg/azure"
+)
+
+const (
+	linuxVMname   = "linuxVM"
+	windowsVMname = "windowsVM"
+
+	customScriptExtensionName = "customScript"
+)
+
+// This example requires that the following environment vars are set:
+//
+// AZURE_TENANT_ID: contains your Azure Active Directory tenant ID or domain
+// AZURE_CLIENT_ID: contains your Azure Active Directory Application Client ID
+// AZURE_CLIENT_SECRET: contains your Azure Active Directory Application Secret
+// AZURE_SUBSCRIPTION_ID: contains your Azure Subscription ID
+//
+// SSH_PUBLIC_KEY is optional: when set, its contents are provisioned as
+// an authorized key on the Linux VM and password authentication is
+// disabled; otherwise the Linux VM falls back to a generated password,
+// same as the Windows VM.
+
+func main() {
+	cntx := context.Background()
+
+	instances, err := azure.NewAzureInstanceSet(azure.Config{
+		SubscriptionID: getEnvVarOrExit("AZURE_SUBSCRIPTION_ID"),
+		ClientID:       getEnvVarOrExit("AZURE_CLIENT_ID"),
+		ClientSecret:   getEnvVarOrExit("AZURE_CLIENT_SECRET"),
+		TenantID:       getEnvVarOrExit("AZURE_TENANT_ID"),
+		ResourceGroup:  "sample-group1",
+		Location:       "eastus",
+		Network:        "vNet",
+		Subnet:         "subnet",
+		StorageAccount: "golangrocksonazurese",
+	})
+	onErrorFail(err, "NewAzureInstanceSet failed")
+	defer instances.DeleteResourceGroup(cntx)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go createVM(cntx, instances, linuxVMname, "Canonical", "UbuntuServer", "16.04.0-LTS", &wg)
+	go createVM(cntx, instances, windowsVMname, "MicrosoftWindowsServer", "WindowsServer", "2016-Datacenter", &wg)
+	wg.Wait()
+
+	fmt.Println("Your Linux VM and Windows VM have been created successfully")
+
+	wg.Add(2)
+	go vmOperations(cntx, instances, linuxVMname, &wg)
+	go vmOperations(cntx, instances, windowsVMname, &wg)
+	wg.Wait()
+
+	listVMs(cntx, instances)
+
+	fmt.Print("Press enter to delete the VMs and other resources created in this sample...")
+	var input string
+	fmt.Scanln(&input)
+
+	wg.Add(2)
+	go deleteVM(cntx, instances, linuxVMname, &wg)
+	go deleteVM(cntx, instances, windowsVMname, &wg)
+	wg.Wait()
+
+	fmt.Println("Delete resource group...")
+	onErrorFail(instances.DeleteResourceGroup(cntx), "DeleteResourceGroup failed")
+}
+
+// createVM creates a VM and prints how to connect to it.
+func createVM(cntx context.Context, instances *azure.AzureInstanceSet, vmName, publisher, offer, sku string, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	fmt.Printf("Create '%s' VM...\n", vmName)
+	result, err := instances.Create(cntx, azure.VMSpec{
+		Name:      vmName,
+		Publisher: publisher,
+		Offer:     offer,
+		Sku:       sku,
+		Tags:      map[string]string{"instance-set": "virtual-machines-go-manage-sample"},
+		OSProfile: osProfileFor(vmName),
+	})
+	onErrorFail(err, "createVM failed")
+
+	if result.VM.OsProfile.AdminPassword != nil {
+		fmt.Printf("Now you can connect to '%s' VM via 'ssh %s@<its public IP>' with password '%s'\n",
+			vmName,
+			*result.VM.OsProfile.AdminUsername,
+			*result.VM.OsProfile.AdminPassword)
+	} else {
+		fmt.Printf("Now you can connect to '%s' VM via 'ssh %s@<its public IP>' using your SSH key\n",
+			vmName,
+			*result.VM.OsProfile.AdminUsername)
+	}
+	fmt.Printf("'%s' VM is reachable at DNS label '%s', using storage account '%s'\n",
+		vmName, result.DomainNameLabel, result.StorageAccount)
+}
+
+// osProfileFor builds the OSProfileOptions for vmName: a Windows
+// configuration for windowsVMname, and for every other (Linux) VM,
+// SSH-key provisioning from SSH_PUBLIC_KEY when set, or a generated
+// password otherwise.
+func osProfileFor(vmName string) azure.OSProfileOptions {
+	if vmName == windowsVMname {
+		return azure.OSProfileOptions{Windows: true}
+	}
+
+	opts := azure.OSProfileOptions{}
+	if key := os.Getenv("SSH_PUBLIC_KEY"); key != "" {
+		opts.SSHPublicKeys = []string{key}
+	}
+	return opts
+}
+
+// vmOperations performs simple VM operations.
+func vmOperations(cntx context.Context, instances *azure.AzureInstanceSet, vmName string, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	fmt.Printf("Performing various operations on '%s' VM\n", vmName)
+
+	fmt.Printf("Get VM '%s' by name\n", vmName)
+	vm, err := instances.Get(cntx, vmName)
+	onErrorFail(err, fmt.Sprintf("Get failed for '%s'", vmName))
+	printVM(*vm)
+
+	fmt.Printf("Attach data disk to VM '%s'\n", vmName)
+	err = instances.AttachDisk(cntx, vmName, azure.DataDiskSpec{Lun: 0, Name: fmt.Sprintf("dataDisks-%s", vmName), SizeGB: 1})
+	onErrorFail(err, fmt.Sprintf("AttachDisk failed for '%s'", vmName))
+
+	fmt.Printf("Detach data disk from VM '%s'\n", vmName)
+	onErrorFail(instances.DetachDisk(cntx, vmName, 0), fmt.Sprintf("DetachDisk failed for '%s'", vmName))
+
+	fmt.Printf("Update OS disk size for VM '%s'\n", vmName)
+	onErrorFail(instances.ResizeOSDisk(cntx, vmName, 256), fmt.Sprintf("ResizeOSDisk failed for '%s'", vmName))
+
+	fmt.Printf("Apply CustomScript extension to VM '%s'\n", vmName)
+	onErrorFail(instances.ApplyExtension(cntx, vmName, customScriptExtension(vmName)), fmt.Sprintf("ApplyExtension failed for '%s'", vmName))
+
+	fmt.Printf("Remove CustomScript extension from VM '%s'\n", vmName)
+	onErrorFail(instances.RemoveExtension(cntx, vmName, customScriptExtensionName), fmt.Sprintf("RemoveExtension failed for '%s'", vmName))
+
+	fmt.Println("Start VM...")
+	onErrorFail(instances.Start(cntx, vmName), fmt.Sprintf("Start failed for '%s'", vmName))
+
+	fmt.Println("Restart VM...")
+	onErrorFail(instances.Restart(cntx, vmName), fmt.Sprintf("Restart failed for '%s'", vmName))
+
+	fmt.Println("Stop VM...")
+	onErrorFail(instances.Stop(cntx, vmName), fmt.Sprintf("Stop failed for '%s'", vmName))
+}
+
+// customScriptExtension builds an ExtensionSpec that writes a marker
+// file to prove post-boot configuration ran, using the Linux or Windows
+// CustomScript extension depending on vmName.
+func customScriptExtension(vmName string) azure.ExtensionSpec {
+	if vmName == windowsVMname {
+		return azure.ExtensionSpec{
+			Name:                    customScriptExtensionName,
+			Publisher:               "Microsoft.Compute",
+			Type:                    "CustomScriptExtension",
+			TypeHandlerVersion:      "1.10",
+			AutoUpgradeMinorVersion: true,
+			ProtectedSettings: map[string]interface{}{
+				"commandToExecute": "powershell -Command \"New-Item -Path C:\\provisioned.txt -ItemType File\"",
+			},
+		}
+	}
+	return azure.ExtensionSpec{
+		Name:                    customScriptExtensionName,
+		Publisher:               "Microsoft.Azure.Extensions",
+		Type:                    "CustomScript",
+		TypeHandlerVersion:      "2.1",
+		AutoUpgradeMinorVersion: true,
+		ProtectedSettings: map[string]interface{}{
+			"commandToExecute": "touch /tmp/provisioned",
+		},
+	}
+}
+
+func listVMs(cntx context.Context, instances *azure.AzureInstanceSet) {
+	fmt.Println("List VMs in subscription...")
+	records, err := instances.Discover(cntx, azure.DiscoverFilter{})
+	onErrorFail(err, "Discover failed")
+	if len(records) > 0 {
+		fmt.Println("VMs in subscription")
+		for _, rec := range records {
+			printVM(rec)
+		}
+	} else {
+		fmt.Println("There are no VMs in this subscription")
+	}
+}
+
+func deleteVM(cntx context.Context, instances *azure.AzureInstanceSet, vmName string, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	fmt.Printf("Delete '%s' virtual machine...\n", vmName)
+	onErrorFail(instances.Delete(cntx, vmName), fmt.Sprintf("Delete failed for '%s'", vmName))
+}
+
+// printVM prints basic info about a Virtual Machine.
+func printVM(rec azure.InstanceRecord) {
+	tags := "\n"
+	if len(rec.Tags) == 0 {
+		tags += "\t\tNo tags yet\n"
+	} else {
+		for k, v := range rec.Tags {
+			tags += fmt.Sprintf("\t\t%s = %s\n", k, v)
+		}
+	}
+	fmt.Printf("Virtual machine '%s'\n", rec.Name)
+	elements := map[string]interface{}{
+		"ID":         rec.ID,
+		"Size":       rec.Size,
+		"Power":      rec.Power,
+		"PrivateIPs": rec.PrivateIPs,
+		"PublicIPs":  rec.PublicIPs,
+		"FQDN":       rec.FQDN,
+		"Tags":       tags}
+	for k, v := range elements {
+		fmt.Printf("\t%s: %v\n", k, v)
+	}
+}
+
+// getEnvVarOrExit returns the value of specified environment variable or terminates if it's not defined.
+func getEnvVarOrExit(varName string) string {
+	value := os.Getenv(varName)
+	if value == "" {
+		fmt.Printf("Missing environment variable '%s'\n", varName)
+		os.Exit(1)
+	}
+
+	return value
+}
+
+// onErrorFail prints a failure message and exits the program if err is not nil.
+func onErrorFail(err error, message string) {
+	if err != nil {
+		fmt.Printf("%s: %s\n", message, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,617 @@
+// Package azure implements InstanceSet for Azure virtual machines, on top
+// of the Azure SDK for Go. It is extracted from the former example.go
+// command-line sample so that it can be embedded in larger Go services.
+package azure
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/compute/mgmt/compute"
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/network/mgmt/network"
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/resources/mgmt/resources"
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/storage/mgmt/storage"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+const vhdURItemplate = "https://%s.blob.core.windows.net/%s/%s.vhd"
+
+// defaultBlobContainer is used for VHD-backed disks when Config.BlobContainer is empty.
+const defaultBlobContainer = "golangcontainer"
+
+// blobContainer returns the blob container VHD-backed disks are stored
+// in, defaulting to defaultBlobContainer when Config.BlobContainer is unset.
+func (a *AzureInstanceSet) blobContainer() string {
+	if a.cfg.BlobContainer != "" {
+		return a.cfg.BlobContainer
+	}
+	return defaultBlobContainer
+}
+
+// AzureInstanceSet manages a set of VM instances in a single Azure
+// resource group. It implements InstanceSet.
+type AzureInstanceSet struct {
+	cfg Config
+
+	groupClient      resources.GroupsClient
+	accountClient    storage.AccountsClient
+	vNetClient       network.VirtualNetworksClient
+	subnetClient     network.SubnetsClient
+	addressClient    network.PublicIPAddressesClient
+	interfacesClient network.InterfacesClient
+	vmClient         compute.VirtualMachinesClient
+	extensionsClient compute.VirtualMachineExtensionsClient
+	dnsCheckClient   network.CheckDNSNameAvailabilityClient
+
+	// networkMu guards ensureNetwork, which lazily creates (or reuses)
+	// shared resources and caches the result in subnet. Without it,
+	// concurrent Create calls (main.go launches several in parallel)
+	// could race both on Azure resource creation and on the
+	// Config.StorageAccount write below.
+	networkMu sync.Mutex
+	subnet    *network.Subnet
+	waitCfg   WaitConfig
+}
+
+var _ InstanceSet = (*AzureInstanceSet)(nil)
+
+// NewAzureInstanceSet builds an AzureInstanceSet and its underlying Azure
+// SDK clients from cfg, authenticating with the service principal
+// credentials it carries. It does not create or check any Azure
+// resources; that happens lazily the first time Create is called.
+func NewAzureInstanceSet(cfg Config) (*AzureInstanceSet, error) {
+	env := azure.PublicCloud
+	if cfg.CloudEnvironment != "" {
+		var err error
+		env, err = azure.EnvironmentFromName(cfg.CloudEnvironment)
+		if err != nil {
+			return nil, fmt.Errorf("unknown cloud environment %q: %s", cfg.CloudEnvironment, err)
+		}
+	}
+
+	ccc := auth.NewClientCredentialsConfig(cfg.ClientID, cfg.ClientSecret, cfg.TenantID)
+	ccc.AADEndpoint = env.ActiveDirectoryEndpoint
+	ccc.Resource = env.ResourceManagerEndpoint
+	authorizer, err := ccc.Authorizer()
+	if err != nil {
+		return nil, fmt.Errorf("building authorizer failed: %s", err)
+	}
+
+	a := &AzureInstanceSet{
+		cfg:     cfg,
+		waitCfg: WaitConfig{PollInterval: cfg.PollInterval, MaxPollInterval: cfg.MaxPollInterval},
+	}
+	a.createClients(cfg.SubscriptionID, authorizer)
+	return a, nil
+}
+
+func (a *AzureInstanceSet) createClients(subscriptionID string, authorizer autorest.Authorizer) {
+	a.groupClient = resources.NewGroupsClient(subscriptionID)
+	a.groupClient.Authorizer = authorizer
+
+	a.accountClient = storage.NewAccountsClient(subscriptionID)
+	a.accountClient.Authorizer = authorizer
+
+	a.vNetClient = network.NewVirtualNetworksClient(subscriptionID)
+	a.vNetClient.BaseClient.Authorizer = authorizer
+
+	a.subnetClient = network.NewSubnetsClient(subscriptionID)
+	a.subnetClient.Authorizer = authorizer
+
+	a.addressClient = network.NewPublicIPAddressesClient(subscriptionID)
+	a.addressClient.Authorizer = authorizer
+
+	a.interfacesClient = network.NewInterfacesClient(subscriptionID)
+	a.interfacesClient.Authorizer = authorizer
+
+	a.vmClient = compute.NewVirtualMachinesClient(subscriptionID)
+	a.vmClient.Authorizer = authorizer
+
+	a.extensionsClient = compute.NewVirtualMachineExtensionsClient(subscriptionID)
+	a.extensionsClient.Authorizer = authorizer
+
+	a.dnsCheckClient = network.NewCheckDNSNameAvailabilityClient(subscriptionID)
+	a.dnsCheckClient.Authorizer = authorizer
+}
+
+// ensureNetwork creates the resource group, storage account, virtual
+// network and subnet that Create needs, the first time it is called, and
+// caches the resulting subnet for subsequent calls. Each step reuses a
+// pre-existing, compatible resource instead of failing, so re-running a
+// caller against the same Config without tearing down its resource group
+// first works.
+func (a *AzureInstanceSet) ensureNetwork(ctx context.Context) (*network.Subnet, error) {
+	a.networkMu.Lock()
+	defer a.networkMu.Unlock()
+
+	if a.subnet != nil {
+		return a.subnet, nil
+	}
+
+	if err := a.ensureResourceGroup(ctx); err != nil {
+		return nil, err
+	}
+
+	// A storage account is only needed for VHD-backed (unmanaged) disks.
+	// Callers that only ever create managed-disk VMs can leave
+	// Config.StorageAccount empty and skip this step entirely.
+	if a.cfg.StorageAccount != "" {
+		used, err := a.ensureStorageAccount(ctx, a.cfg.StorageAccount)
+		if err != nil {
+			return nil, err
+		}
+		a.cfg.StorageAccount = used
+	}
+
+	if err := a.ensureVirtualNetwork(ctx); err != nil {
+		return nil, err
+	}
+
+	subnetInfo, err := a.ensureSubnet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.subnet = subnetInfo
+	return a.subnet, nil
+}
+
+// Create creates a VM from spec, along with the public IP address and NIC
+// it needs, and returns the created VM along with the storage account
+// and DNS label names that were actually used.
+func (a *AzureInstanceSet) Create(ctx context.Context, spec VMSpec) (*CreateResult, error) {
+	subnetInfo, err := a.ensureNetwork(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, nicParameters, domainNameLabel, err := a.createPIPandNIC(ctx, spec.Name, subnetInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	vm, err := a.setVMparameters(spec, *nicParameters.ID)
+	if err != nil {
+		return nil, err
+	}
+	vmFuture, err := a.vmClient.CreateOrUpdate(ctx, a.cfg.ResourceGroup, spec.Name, vm)
+	if err != nil {
+		return nil, fmt.Errorf("vmClient.CreateOrUpdate failed for %q: %s", spec.Name, err)
+	}
+	if err := a.waitForCompletion(ctx, &vmFuture, a.waitCfg); err != nil {
+		return nil, fmt.Errorf("vmClient.CreateOrUpdate did not complete for %q: %s", spec.Name, err)
+	}
+	return &CreateResult{
+		VM:              &vm,
+		StorageAccount:  a.cfg.StorageAccount,
+		DomainNameLabel: domainNameLabel,
+	}, nil
+}
+
+// createPIPandNIC creates a public IP address and a network interface in
+// the given subnet, ready to be used to create a virtual machine. It
+// returns the domain name label actually used for the public IP,
+// alongside the address and interface themselves.
+func (a *AzureInstanceSet) createPIPandNIC(ctx context.Context, vmName string, subnetInfo *network.Subnet) (*network.PublicIPAddress, *network.Interface, string, error) {
+	IPname := fmt.Sprintf("pip-%s", vmName)
+
+	nameHint := strings.ToLower(vmName)
+	if len(nameHint) > 5 {
+		nameHint = nameHint[:5]
+	}
+	domainNameLabel, err := a.reuseOrEnsureDomainNameLabel(ctx, IPname, fmt.Sprintf("azuresamplese-%s", nameHint))
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	pipParameters := network.PublicIPAddress{
+		Location: &a.cfg.Location,
+		PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
+			DNSSettings: &network.PublicIPAddressDNSSettings{
+				DomainNameLabel: to.StringPtr(domainNameLabel),
+			},
+		},
+	}
+	pipFuture, err := a.addressClient.CreateOrUpdate(ctx, a.cfg.ResourceGroup, IPname, pipParameters)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("addressClient.CreateOrUpdate %q failed: %s", IPname, err)
+	}
+	if err := a.waitForCompletion(ctx, &pipFuture, a.waitCfg); err != nil {
+		return nil, nil, "", fmt.Errorf("addressClient.CreateOrUpdate %q did not complete: %s", IPname, err)
+	}
+
+	publicIPaddress, err := a.addressClient.Get(ctx, a.cfg.ResourceGroup, IPname, "")
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("addressClient.Get for IP %q failed: %s", IPname, err)
+	}
+
+	nicName := fmt.Sprintf("nic-%s", vmName)
+	nicParameters := network.Interface{
+		Location: &a.cfg.Location,
+		InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+			IPConfigurations: &[]network.InterfaceIPConfiguration{
+				{
+					Name: to.StringPtr(fmt.Sprintf("IPconfig-%s", vmName)),
+					InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
+						PublicIPAddress:           &publicIPaddress,
+						PrivateIPAllocationMethod: network.Dynamic,
+						Subnet:                    subnetInfo,
+					},
+				},
+			},
+		},
+	}
+	nicFuture, err := a.interfacesClient.CreateOrUpdate(ctx, a.cfg.ResourceGroup, nicName, nicParameters)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("interfacesClient.CreateOrUpdate for NIC %q failed: %s", nicName, err)
+	}
+	if err := a.waitForCompletion(ctx, &nicFuture, a.waitCfg); err != nil {
+		return nil, nil, "", fmt.Errorf("interfacesClient.CreateOrUpdate for NIC %q did not complete: %s", nicName, err)
+	}
+
+	nicParameters, err = a.interfacesClient.Get(ctx, a.cfg.ResourceGroup, nicName, "")
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("interfacesClient.Get for NIC %q failed: %s", nicName, err)
+	}
+
+	return &publicIPaddress, &nicParameters, domainNameLabel, nil
+}
+
+// setVMparameters builds the VirtualMachine argument for creating or
+// updating a VM from spec.
+func (a *AzureInstanceSet) setVMparameters(spec VMSpec, nicID string) (compute.VirtualMachine, error) {
+	osProfile, err := a.osProfile(spec)
+	if err != nil {
+		return compute.VirtualMachine{}, err
+	}
+
+	return compute.VirtualMachine{
+		Location: &a.cfg.Location,
+		Tags:     toTags(spec.Tags),
+		VirtualMachineProperties: &compute.VirtualMachineProperties{
+			HardwareProfile: &compute.HardwareProfile{
+				VMSize: compute.StandardA1,
+			},
+			StorageProfile: &compute.StorageProfile{
+				ImageReference: a.imageReference(spec),
+				OsDisk:         a.osDisk(spec),
+			},
+			OsProfile: osProfile,
+			NetworkProfile: &compute.NetworkProfile{
+				NetworkInterfaces: &[]compute.NetworkInterfaceReference{
+					{
+						ID: &nicID,
+						NetworkInterfaceReferenceProperties: &compute.NetworkInterfaceReferenceProperties{
+							Primary: to.BoolPtr(true),
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// osProfile builds the OSProfile for spec: SSH-key provisioning with
+// password authentication disabled for Linux VMs that supply keys, a
+// WindowsConfiguration for Windows VMs, and a randomly generated admin
+// password otherwise.
+func (a *AzureInstanceSet) osProfile(spec VMSpec) (*compute.OSProfile, error) {
+	opts := spec.OSProfile
+	username := opts.AdminUsername
+	if username == "" {
+		username = "notadmin"
+	}
+
+	profile := &compute.OSProfile{
+		ComputerName:  &spec.Name,
+		AdminUsername: &username,
+	}
+	if opts.CustomData != "" {
+		profile.CustomData = &opts.CustomData
+	}
+
+	if opts.Windows {
+		password, err := generatePassword()
+		if err != nil {
+			return nil, err
+		}
+		profile.AdminPassword = &password
+		profile.WindowsConfiguration = &compute.WindowsConfiguration{}
+		return profile, nil
+	}
+
+	if len(opts.SSHPublicKeys) > 0 {
+		keys := make([]compute.SSHPublicKey, len(opts.SSHPublicKeys))
+		for i, key := range opts.SSHPublicKeys {
+			keys[i] = compute.SSHPublicKey{
+				Path:    to.StringPtr(fmt.Sprintf("/home/%s/.ssh/authorized_keys", username)),
+				KeyData: to.StringPtr(key),
+			}
+		}
+		profile.LinuxConfiguration = &compute.LinuxConfiguration{
+			DisablePasswordAuthentication: to.BoolPtr(true),
+			SSH: &compute.SSHConfiguration{
+				PublicKeys: &keys,
+			},
+		}
+		return profile, nil
+	}
+
+	password, err := generatePassword()
+	if err != nil {
+		return nil, err
+	}
+	profile.AdminPassword = &password
+	return profile, nil
+}
+
+// generatePassword returns a random admin password, used whenever a VM is
+// created without SSH-key provisioning.
+func generatePassword() (string, error) {
+	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789!@#$%^&*"
+	b := make([]byte, 24)
+	if _, err := cryptorand.Read(b); err != nil {
+		return "", fmt.Errorf("generating admin password failed: %s", err)
+	}
+	for i, c := range b {
+		b[i] = alphabet[int(c)%len(alphabet)]
+	}
+	return string(b), nil
+}
+
+// toTags converts a plain string map into the *map[string]*string shape
+// the Azure SDK expects, or nil if tags is empty.
+func toTags(tags map[string]string) *map[string]*string {
+	if len(tags) == 0 {
+		return nil
+	}
+	m := make(map[string]*string, len(tags))
+	for k, v := range tags {
+		v := v
+		m[k] = &v
+	}
+	return &m
+}
+
+// imageReference builds the ImageReference for spec: a resource ID when
+// spec.ImageID is set (custom image or shared image gallery version), or
+// a marketplace publisher/offer/sku/version triple otherwise.
+func (a *AzureInstanceSet) imageReference(spec VMSpec) *compute.ImageReference {
+	if spec.ImageID != "" {
+		return &compute.ImageReference{ID: &spec.ImageID}
+	}
+
+	version := spec.Version
+	if version == "" {
+		version = "latest"
+	}
+	return &compute.ImageReference{
+		Publisher: &spec.Publisher,
+		Offer:     &spec.Offer,
+		Sku:       &spec.Sku,
+		Version:   &version,
+	}
+}
+
+// osDisk builds the OSDisk for spec: a Managed Disk when spec.ManagedDisk
+// is set, or a VHD blob in Config.StorageAccount otherwise.
+func (a *AzureInstanceSet) osDisk(spec VMSpec) *compute.OSDisk {
+	disk := &compute.OSDisk{
+		Name:         to.StringPtr(fmt.Sprintf("%s-osDisk", spec.Name)),
+		CreateOption: compute.DiskCreateOptionTypesFromImage,
+	}
+	if spec.ManagedDisk {
+		disk.ManagedDisk = &compute.ManagedDiskParameters{
+			StorageAccountType: spec.StorageAccountType,
+		}
+	} else {
+		disk.Vhd = &compute.VirtualHardDisk{
+			URI: to.StringPtr(fmt.Sprintf(vhdURItemplate, a.cfg.StorageAccount, a.blobContainer(), spec.Name)),
+		}
+	}
+	return disk
+}
+
+// List returns every VM in the instance set's resource group, following
+// List's nextLink until it is exhausted. Unlike Discover, which lists
+// subscription-wide for a service-discovery view, List is scoped to
+// a.cfg.ResourceGroup so that embedding callers managing several
+// resource groups each see only their own VMs.
+func (a *AzureInstanceSet) List(ctx context.Context) ([]compute.VirtualMachine, error) {
+	page, err := a.vmClient.List(ctx, a.cfg.ResourceGroup)
+	if err != nil {
+		return nil, fmt.Errorf("vmClient.List failed: %s", err)
+	}
+
+	var all []compute.VirtualMachine
+	for {
+		all = append(all, page.Values()...)
+		if !page.NotDone() {
+			break
+		}
+		if err := page.NextWithContext(ctx); err != nil {
+			return nil, fmt.Errorf("vmClient.List paging failed: %s", err)
+		}
+	}
+	return all, nil
+}
+
+// Get returns the named VM, with its InstanceView populated.
+func (a *AzureInstanceSet) Get(ctx context.Context, name string) (*compute.VirtualMachine, error) {
+	vm, err := a.vmClient.Get(ctx, a.cfg.ResourceGroup, name, compute.InstanceView)
+	if err != nil {
+		return nil, fmt.Errorf("vmClient.Get failed for %q: %s", name, err)
+	}
+	return &vm, nil
+}
+
+// Delete deletes the named VM.
+func (a *AzureInstanceSet) Delete(ctx context.Context, name string) error {
+	f, err := a.vmClient.Delete(ctx, a.cfg.ResourceGroup, name)
+	if err != nil {
+		return fmt.Errorf("vmClient.Delete failed for %q: %s", name, err)
+	}
+	if err := a.waitForCompletion(ctx, &f, a.waitCfg); err != nil {
+		return fmt.Errorf("vmClient.Delete did not complete for %q: %s", name, err)
+	}
+	return nil
+}
+
+// Stop powers off the named VM. The VM stays allocated; it is not
+// deallocated.
+func (a *AzureInstanceSet) Stop(ctx context.Context, name string) error {
+	f, err := a.vmClient.PowerOff(ctx, a.cfg.ResourceGroup, name)
+	if err != nil {
+		return fmt.Errorf("vmClient.PowerOff failed for %q: %s", name, err)
+	}
+	if err := a.waitForCompletion(ctx, &f, a.waitCfg); err != nil {
+		return fmt.Errorf("vmClient.PowerOff did not complete for %q: %s", name, err)
+	}
+	return nil
+}
+
+// Start starts the named VM.
+func (a *AzureInstanceSet) Start(ctx context.Context, name string) error {
+	f, err := a.vmClient.Start(ctx, a.cfg.ResourceGroup, name)
+	if err != nil {
+		return fmt.Errorf("vmClient.Start failed for %q: %s", name, err)
+	}
+	if err := a.waitForCompletion(ctx, &f, a.waitCfg); err != nil {
+		return fmt.Errorf("vmClient.Start did not complete for %q: %s", name, err)
+	}
+	return nil
+}
+
+// Restart restarts the named VM.
+func (a *AzureInstanceSet) Restart(ctx context.Context, name string) error {
+	f, err := a.vmClient.Restart(ctx, a.cfg.ResourceGroup, name)
+	if err != nil {
+		return fmt.Errorf("vmClient.Restart failed for %q: %s", name, err)
+	}
+	if err := a.waitForCompletion(ctx, &f, a.waitCfg); err != nil {
+		return fmt.Errorf("vmClient.Restart did not complete for %q: %s", name, err)
+	}
+	return nil
+}
+
+// AttachDisk attaches a new data disk to the named VM, via a
+// CreateOrUpdate of the VM with an extra entry in its DataDisks.
+func (a *AzureInstanceSet) AttachDisk(ctx context.Context, name string, disk DataDiskSpec) error {
+	vm, err := a.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	dataDisks := []compute.DataDisk{}
+	if vm.StorageProfile.DataDisks != nil {
+		dataDisks = *vm.StorageProfile.DataDisks
+	}
+	newDisk := compute.DataDisk{
+		Lun:          to.Int32Ptr(disk.Lun),
+		Name:         to.StringPtr(disk.Name),
+		CreateOption: compute.DiskCreateOptionTypesEmpty,
+		DiskSizeGB:   to.Int32Ptr(disk.SizeGB),
+	}
+	if disk.ManagedDisk {
+		newDisk.ManagedDisk = &compute.ManagedDiskParameters{
+			StorageAccountType: disk.StorageAccountType,
+		}
+	} else {
+		newDisk.Vhd = &compute.VirtualHardDisk{
+			URI: to.StringPtr(fmt.Sprintf(vhdURItemplate, a.cfg.StorageAccount, a.blobContainer(), disk.Name)),
+		}
+	}
+	dataDisks = append(dataDisks, newDisk)
+	vm.StorageProfile.DataDisks = &dataDisks
+
+	f, err := a.vmClient.CreateOrUpdate(ctx, a.cfg.ResourceGroup, name, *vm)
+	if err != nil {
+		return fmt.Errorf("vmClient.CreateOrUpdate failed for %q: %s", name, err)
+	}
+	if err := a.waitForCompletion(ctx, &f, a.waitCfg); err != nil {
+		return fmt.Errorf("vmClient.CreateOrUpdate did not complete for %q: %s", name, err)
+	}
+	return nil
+}
+
+// DetachDisk detaches the data disk at the given LUN from the named VM.
+func (a *AzureInstanceSet) DetachDisk(ctx context.Context, name string, lun int32) error {
+	vm, err := a.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if vm.StorageProfile.DataDisks == nil {
+		return nil
+	}
+	remaining := []compute.DataDisk{}
+	for _, d := range *vm.StorageProfile.DataDisks {
+		if d.Lun == nil || *d.Lun != lun {
+			remaining = append(remaining, d)
+		}
+	}
+	vm.StorageProfile.DataDisks = &remaining
+
+	f, err := a.vmClient.CreateOrUpdate(ctx, a.cfg.ResourceGroup, name, *vm)
+	if err != nil {
+		return fmt.Errorf("vmClient.CreateOrUpdate failed for %q: %s", name, err)
+	}
+	if err := a.waitForCompletion(ctx, &f, a.waitCfg); err != nil {
+		return fmt.Errorf("vmClient.CreateOrUpdate did not complete for %q: %s", name, err)
+	}
+	return nil
+}
+
+// ResizeOSDisk deallocates the named VM and grows its OS disk to at
+// least sizeGB.
+func (a *AzureInstanceSet) ResizeOSDisk(ctx context.Context, name string, sizeGB int32) error {
+	vm, err := a.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	deallocateFuture, err := a.vmClient.Deallocate(ctx, a.cfg.ResourceGroup, name)
+	if err != nil {
+		return fmt.Errorf("vmClient.Deallocate failed for %q: %s", name, err)
+	}
+	if err := a.waitForCompletion(ctx, &deallocateFuture, a.waitCfg); err != nil {
+		return fmt.Errorf("vmClient.Deallocate did not complete for %q: %s", name, err)
+	}
+
+	current := int32(0)
+	if vm.StorageProfile.OsDisk.DiskSizeGB != nil {
+		current = *vm.StorageProfile.OsDisk.DiskSizeGB
+	}
+	if sizeGB <= current {
+		sizeGB = current + 10
+	}
+	vm.StorageProfile.OsDisk.DiskSizeGB = to.Int32Ptr(sizeGB)
+
+	f, err := a.vmClient.CreateOrUpdate(ctx, a.cfg.ResourceGroup, name, *vm)
+	if err != nil {
+		return fmt.Errorf("vmClient.CreateOrUpdate failed for %q: %s", name, err)
+	}
+	if err := a.waitForCompletion(ctx, &f, a.waitCfg); err != nil {
+		return fmt.Errorf("vmClient.CreateOrUpdate did not complete for %q: %s", name, err)
+	}
+	return nil
+}
+
+// DeleteResourceGroup deletes the resource group backing this instance
+// set, and everything in it.
+func (a *AzureInstanceSet) DeleteResourceGroup(ctx context.Context) error {
+	f, err := a.groupClient.Delete(ctx, a.cfg.ResourceGroup)
+	if err != nil {
+		return fmt.Errorf("groupClient.Delete failed for %q: %s", a.cfg.ResourceGroup, err)
+	}
+	if err := a.waitForCompletion(ctx, &f, a.waitCfg); err != nil {
+		return fmt.Errorf("groupClient.Delete did not complete for %q: %s", a.cfg.ResourceGroup, err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package azure
+
+import "time"
+
+// Config holds everything an AzureInstanceSet needs to authenticate
+// against Azure and to know which resources it should create VMs in.
+// It is passed explicitly to NewAzureInstanceSet instead of being read
+// from package-level globals, so a caller (CLI, dispatcher, CSI driver,
+// machine controller, ...) can construct and run several independent
+// instance sets in the same process.
+type Config struct {
+	SubscriptionID string
+	ClientID       string
+	ClientSecret   string
+	TenantID       string
+
+	// CloudEnvironment is the name of an azure.Environment, e.g.
+	// "AzurePublicCloud" or "AzureUSGovernmentCloud". Empty means
+	// azure.PublicCloud.
+	CloudEnvironment string
+
+	ResourceGroup string
+	Location      string
+	Network       string
+	Subnet        string
+
+	StorageAccount string
+
+	// BlobContainer holds VHD-backed (unmanaged) OS and data disks.
+	// Defaults to "golangcontainer" when empty.
+	BlobContainer string
+
+	// PollInterval and MaxPollInterval tune waitForCompletion's polling
+	// of ARM long-running operations. Both default when zero; see
+	// WaitConfig.
+	PollInterval    time.Duration
+	MaxPollInterval time.Duration
+}
@@ -0,0 +1,226 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/compute/mgmt/compute"
+)
+
+// InstanceRecord is a fully resolved snapshot of a VM, in the spirit of a
+// service-discovery record: everything a caller needs to reach the
+// instance or tell what it's running, without further API calls.
+type InstanceRecord struct {
+	ID       string
+	Name     string
+	Size     string
+	OSType   string
+	Tags     map[string]string
+	Power    string // e.g. "running", "deallocated"; empty if unknown.
+	ScaleSet string // resource ID of the owning VM scale set, if any.
+
+	PrivateIPs []string
+	PublicIPs  []string
+	FQDN       string
+}
+
+// DiscoverFilter controls a Discover call.
+type DiscoverFilter struct {
+	// Concurrency bounds how many VMs are resolved (NIC/IP lookups) in
+	// parallel. Defaults to 4 when <= 0.
+	Concurrency int
+}
+
+// Discover lists every VM visible to this instance set's credentials
+// across the whole subscription, resolving each one's NICs and public IP
+// addresses. Paging through ListAll's nextLink and per-VM resolution are
+// both transparent to the caller.
+func (a *AzureInstanceSet) Discover(ctx context.Context, filter DiscoverFilter) ([]InstanceRecord, error) {
+	vms, err := a.listAllPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := filter.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	records := make([]InstanceRecord, len(vms))
+	errs := make([]error, len(vms))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, vm := range vms {
+		wg.Add(1)
+		go func(i int, vm compute.VirtualMachine) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			records[i], errs[i] = a.resolveInstance(ctx, vm)
+		}(i, vm)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// listAllPages returns every VM in the subscription, following ListAll's
+// nextLink until it is exhausted.
+func (a *AzureInstanceSet) listAllPages(ctx context.Context) ([]compute.VirtualMachine, error) {
+	page, err := a.vmClient.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("vmClient.ListAll failed: %s", err)
+	}
+
+	var all []compute.VirtualMachine
+	for {
+		all = append(all, page.Values()...)
+		if !page.NotDone() {
+			break
+		}
+		if err := page.NextWithContext(ctx); err != nil {
+			return nil, fmt.Errorf("vmClient.ListAll paging failed: %s", err)
+		}
+	}
+	return all, nil
+}
+
+// resolveInstance re-fetches vm with its InstanceView expanded for power
+// state, then resolves its NICs and any attached public IP addresses.
+func (a *AzureInstanceSet) resolveInstance(ctx context.Context, vm compute.VirtualMachine) (InstanceRecord, error) {
+	rec := InstanceRecord{Tags: fromTags(vm.Tags)}
+	if vm.ID != nil {
+		rec.ID = *vm.ID
+	}
+	if vm.Name != nil {
+		rec.Name = *vm.Name
+	}
+
+	resourceGroup := resourceGroupFromID(rec.ID)
+	if resourceGroup != "" && rec.Name != "" {
+		full, err := a.vmClient.Get(ctx, resourceGroup, rec.Name, compute.InstanceView)
+		if err != nil {
+			return rec, fmt.Errorf("vmClient.Get failed for %q: %s", rec.Name, err)
+		}
+		vm = full
+	}
+
+	if vm.HardwareProfile != nil {
+		rec.Size = string(vm.HardwareProfile.VMSize)
+	}
+	if vm.StorageProfile != nil && vm.StorageProfile.OsDisk != nil {
+		rec.OSType = string(vm.StorageProfile.OsDisk.OsType)
+	}
+	if vm.VirtualMachineScaleSet != nil && vm.VirtualMachineScaleSet.ID != nil {
+		rec.ScaleSet = *vm.VirtualMachineScaleSet.ID
+	}
+	rec.Power = powerState(vm.InstanceView)
+
+	if vm.NetworkProfile == nil || vm.NetworkProfile.NetworkInterfaces == nil {
+		return rec, nil
+	}
+	for _, nicRef := range *vm.NetworkProfile.NetworkInterfaces {
+		if nicRef.ID == nil {
+			continue
+		}
+		if err := a.resolveNIC(ctx, *nicRef.ID, &rec); err != nil {
+			return rec, err
+		}
+	}
+	return rec, nil
+}
+
+// resolveNIC fetches the NIC at nicID and appends its private IPs, and
+// the IPs and FQDN of any attached public IP addresses, to rec.
+func (a *AzureInstanceSet) resolveNIC(ctx context.Context, nicID string, rec *InstanceRecord) error {
+	resourceGroup := resourceGroupFromID(nicID)
+	nicName := lastPathSegment(nicID)
+	nic, err := a.interfacesClient.Get(ctx, resourceGroup, nicName, "")
+	if err != nil {
+		return fmt.Errorf("interfacesClient.Get failed for %q: %s", nicName, err)
+	}
+	if nic.IPConfigurations == nil {
+		return nil
+	}
+
+	for _, ipConfig := range *nic.IPConfigurations {
+		if ipConfig.PrivateIPAddress != nil {
+			rec.PrivateIPs = append(rec.PrivateIPs, *ipConfig.PrivateIPAddress)
+		}
+		if ipConfig.PublicIPAddress == nil || ipConfig.PublicIPAddress.ID == nil {
+			continue
+		}
+
+		pipResourceGroup := resourceGroupFromID(*ipConfig.PublicIPAddress.ID)
+		pipName := lastPathSegment(*ipConfig.PublicIPAddress.ID)
+		pip, err := a.addressClient.Get(ctx, pipResourceGroup, pipName, "")
+		if err != nil {
+			return fmt.Errorf("addressClient.Get failed for %q: %s", pipName, err)
+		}
+		if pip.IPAddress != nil {
+			rec.PublicIPs = append(rec.PublicIPs, *pip.IPAddress)
+		}
+		if pip.DNSSettings != nil && pip.DNSSettings.Fqdn != nil {
+			rec.FQDN = *pip.DNSSettings.Fqdn
+		}
+	}
+	return nil
+}
+
+// powerState returns the "PowerState/..." status code from iv, with the
+// "PowerState/" prefix stripped, or "" if iv has none.
+func powerState(iv *compute.VirtualMachineInstanceView) string {
+	if iv == nil || iv.Statuses == nil {
+		return ""
+	}
+	for _, status := range *iv.Statuses {
+		if status.Code == nil {
+			continue
+		}
+		if state := strings.TrimPrefix(*status.Code, "PowerState/"); state != *status.Code {
+			return state
+		}
+	}
+	return ""
+}
+
+// resourceGroupFromID extracts the resource group name from an ARM
+// resource ID of the form
+// "/subscriptions/{sub}/resourceGroups/{rg}/providers/...".
+func resourceGroupFromID(id string) string {
+	parts := strings.Split(id, "/")
+	for i, p := range parts {
+		if strings.EqualFold(p, "resourceGroups") && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// lastPathSegment returns the last "/"-separated segment of id.
+func lastPathSegment(id string) string {
+	parts := strings.Split(id, "/")
+	return parts[len(parts)-1]
+}
+
+// fromTags converts the Azure SDK's *map[string]*string tag shape into a
+// plain string map, or nil if tags is nil/empty.
+func fromTags(tags *map[string]*string) map[string]string {
+	if tags == nil || len(*tags) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(*tags))
+	for k, v := range *tags {
+		if v != nil {
+			m[k] = *v
+		}
+	}
+	return m
+}
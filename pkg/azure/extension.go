@@ -0,0 +1,68 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/compute/mgmt/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+// ExtensionSpec describes a VM extension to install for post-boot
+// configuration, e.g. Microsoft.Azure.Extensions/CustomScript on Linux
+// or Microsoft.Compute/CustomScriptExtension on Windows.
+type ExtensionSpec struct {
+	// Name identifies the extension resource on the VM, e.g. "customScript".
+	Name string
+
+	Publisher               string
+	Type                    string
+	TypeHandlerVersion      string
+	AutoUpgradeMinorVersion bool
+
+	// Settings is the extension's public configuration, e.g. script
+	// URIs and the command to run. It is marshalled as-is into the
+	// extension's Settings property.
+	Settings map[string]interface{}
+
+	// ProtectedSettings is encrypted at rest and omitted from Get
+	// responses. It carries secrets the extension needs, e.g. storage
+	// account keys or inline scripts, instead of Settings.
+	ProtectedSettings map[string]interface{}
+}
+
+// ApplyExtension installs or updates ext on the named VM.
+func (a *AzureInstanceSet) ApplyExtension(ctx context.Context, vmName string, ext ExtensionSpec) error {
+	parameters := compute.VirtualMachineExtension{
+		Location: to.StringPtr(a.cfg.Location),
+		VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+			Publisher:               to.StringPtr(ext.Publisher),
+			Type:                    to.StringPtr(ext.Type),
+			TypeHandlerVersion:      to.StringPtr(ext.TypeHandlerVersion),
+			AutoUpgradeMinorVersion: to.BoolPtr(ext.AutoUpgradeMinorVersion),
+			Settings:                &ext.Settings,
+			ProtectedSettings:       &ext.ProtectedSettings,
+		},
+	}
+
+	f, err := a.extensionsClient.CreateOrUpdate(ctx, a.cfg.ResourceGroup, vmName, ext.Name, parameters)
+	if err != nil {
+		return fmt.Errorf("extensionsClient.CreateOrUpdate failed for %q on %q: %s", ext.Name, vmName, err)
+	}
+	if err := a.waitForCompletion(ctx, &f, a.waitCfg); err != nil {
+		return fmt.Errorf("extensionsClient.CreateOrUpdate did not complete for %q on %q: %s", ext.Name, vmName, err)
+	}
+	return nil
+}
+
+// RemoveExtension uninstalls the named extension from the named VM.
+func (a *AzureInstanceSet) RemoveExtension(ctx context.Context, vmName, extensionName string) error {
+	f, err := a.extensionsClient.Delete(ctx, a.cfg.ResourceGroup, vmName, extensionName)
+	if err != nil {
+		return fmt.Errorf("extensionsClient.Delete failed for %q on %q: %s", extensionName, vmName, err)
+	}
+	if err := a.waitForCompletion(ctx, &f, a.waitCfg); err != nil {
+		return fmt.Errorf("extensionsClient.Delete did not complete for %q on %q: %s", extensionName, vmName, err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package azure
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/compute/mgmt/compute"
+)
+
+// InstanceSet is implemented by drivers that manage a set of virtual
+// machine instances in a cloud provider, in the spirit of the Arvados
+// cloud-driver InstanceSet interface. AzureInstanceSet is the only
+// implementation in this package.
+type InstanceSet interface {
+	// Create creates a new VM from spec and returns it, along with the
+	// storage account and DNS label names actually used, which can
+	// differ from the requested ones if a naming collision forced a
+	// randomized suffix.
+	Create(ctx context.Context, spec VMSpec) (*CreateResult, error)
+
+	// List returns every VM in the instance set's resource group.
+	List(ctx context.Context) ([]compute.VirtualMachine, error)
+
+	// Get returns the named VM.
+	Get(ctx context.Context, name string) (*compute.VirtualMachine, error)
+
+	// Delete deletes the named VM.
+	Delete(ctx context.Context, name string) error
+
+	// Stop powers off the named VM without deallocating it.
+	Stop(ctx context.Context, name string) error
+
+	// Start starts the named VM.
+	Start(ctx context.Context, name string) error
+
+	// Restart restarts the named VM.
+	Restart(ctx context.Context, name string) error
+
+	// AttachDisk attaches a new data disk to the named VM.
+	AttachDisk(ctx context.Context, name string, disk DataDiskSpec) error
+
+	// DetachDisk detaches the data disk at the given LUN from the named VM.
+	DetachDisk(ctx context.Context, name string, lun int32) error
+
+	// Discover lists every VM visible to this instance set's
+	// credentials, resolving each one's NICs and public IP addresses
+	// into a fully populated InstanceRecord.
+	Discover(ctx context.Context, filter DiscoverFilter) ([]InstanceRecord, error)
+
+	// ApplyExtension installs or updates a VM extension, e.g. a
+	// CustomScript extension that bootstraps software after provisioning.
+	ApplyExtension(ctx context.Context, vmName string, ext ExtensionSpec) error
+
+	// RemoveExtension uninstalls the named extension from the named VM.
+	RemoveExtension(ctx context.Context, vmName, extensionName string) error
+}
+
+// CreateResult is the outcome of a successful Create call.
+type CreateResult struct {
+	VM *compute.VirtualMachine
+
+	// StorageAccount is the storage account backing the VM's VHDs, if
+	// any. It is Config.StorageAccount unless that name collided with
+	// one already taken, in which case it carries a randomized suffix.
+	StorageAccount string
+
+	// DomainNameLabel is the DNS label of the VM's public IP address.
+	// It is derived from the VM's name unless that derived label
+	// collided with one already taken, in which case it carries a
+	// randomized suffix.
+	DomainNameLabel string
+}
+
+// VMSpec describes a VM to create.
+type VMSpec struct {
+	Name string
+
+	// Marketplace image, used when ImageID is empty.
+	Publisher string
+	Offer     string
+	Sku       string
+	Version   string // defaults to "latest"
+
+	// ImageID is the resource ID of a custom image or a shared image
+	// gallery image version, e.g.
+	// "/subscriptions/.../resourceGroups/.../providers/Microsoft.Compute/images/myImage".
+	// When set, it takes precedence over Publisher/Offer/Sku/Version.
+	ImageID string
+
+	// ManagedDisk selects an Azure Managed Disk for the OS disk instead
+	// of a VHD blob in Config.StorageAccount. StorageAccountType is
+	// required when ManagedDisk is true, e.g. compute.StorageAccountTypesPremiumLRS.
+	ManagedDisk        bool
+	StorageAccountType compute.StorageAccountTypes
+
+	OSProfile OSProfileOptions
+
+	// Tags are recorded on the created VM, e.g. an instance or instance-set
+	// tag so that concurrent runs against the same resource group don't
+	// collide.
+	Tags map[string]string
+}
+
+// OSProfileOptions controls how a VM is provisioned for first boot.
+type OSProfileOptions struct {
+	// AdminUsername defaults to "notadmin" when empty.
+	AdminUsername string
+
+	// SSHPublicKeys, if non-empty, provisions each key as an authorized
+	// key for AdminUsername and disables password authentication. Linux
+	// only; ignored when Windows is true.
+	SSHPublicKeys []string
+
+	// CustomData is base64-encoded cloud-init (Linux) or first-boot
+	// (Windows) user data.
+	CustomData string
+
+	// Windows selects a WindowsConfiguration OS profile instead of a
+	// LinuxConfiguration one.
+	Windows bool
+}
+
+// DataDiskSpec describes a data disk to attach to an existing VM.
+type DataDiskSpec struct {
+	Lun    int32
+	Name   string
+	SizeGB int32
+
+	// ManagedDisk selects an Azure Managed Disk instead of a VHD blob in
+	// Config.StorageAccount. StorageAccountType is required when
+	// ManagedDisk is true.
+	ManagedDisk        bool
+	StorageAccountType compute.StorageAccountTypes
+}
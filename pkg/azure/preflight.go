@@ -0,0 +1,221 @@
+package azure
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/network/mgmt/network"
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/resources/mgmt/resources"
+	"github.com/Azure/azure-sdk-for-go/profiles/latest/storage/mgmt/storage"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+// maxNameCollisionRetries bounds how many randomized suffixes
+// ensureStorageAccount and ensureDomainNameLabel will try before giving
+// up on a globally-unique Azure name.
+const maxNameCollisionRetries = 5
+
+// ensureResourceGroup creates the resource group, reusing it as-is if it
+// already exists in the configured location.
+func (a *AzureInstanceSet) ensureResourceGroup(ctx context.Context) error {
+	existing, err := a.groupClient.Get(ctx, a.cfg.ResourceGroup)
+	switch {
+	case err == nil:
+		if existing.Location != nil && !strings.EqualFold(*existing.Location, a.cfg.Location) {
+			return fmt.Errorf("resource group %q already exists in location %q, want %q", a.cfg.ResourceGroup, *existing.Location, a.cfg.Location)
+		}
+		return nil
+	case !isNotFound(err):
+		return fmt.Errorf("groupClient.Get failed for %q: %s", a.cfg.ResourceGroup, err)
+	}
+
+	if _, err := a.groupClient.CreateOrUpdate(ctx, a.cfg.ResourceGroup, resources.Group{Location: &a.cfg.Location}); err != nil {
+		return fmt.Errorf("groupClient.CreateOrUpdate failed for resource group %q: %s", a.cfg.ResourceGroup, err)
+	}
+	return nil
+}
+
+// ensureVirtualNetwork creates the configured virtual network, reusing
+// it as-is if it already exists in the configured location.
+func (a *AzureInstanceSet) ensureVirtualNetwork(ctx context.Context) error {
+	existing, err := a.vNetClient.Get(ctx, a.cfg.ResourceGroup, a.cfg.Network, "")
+	switch {
+	case err == nil:
+		if existing.Location != nil && !strings.EqualFold(*existing.Location, a.cfg.Location) {
+			return fmt.Errorf("virtual network %q already exists in location %q, want %q", a.cfg.Network, *existing.Location, a.cfg.Location)
+		}
+		return nil
+	case !isNotFound(err):
+		return fmt.Errorf("vNetClient.Get failed for %q: %s", a.cfg.Network, err)
+	}
+
+	vNetParameters := network.VirtualNetwork{
+		Location: &a.cfg.Location,
+		VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+			AddressSpace: &network.AddressSpace{
+				AddressPrefixes: &[]string{"10.0.0.0/16"},
+			},
+		},
+	}
+	vNetFuture, err := a.vNetClient.CreateOrUpdate(ctx, a.cfg.ResourceGroup, a.cfg.Network, vNetParameters)
+	if err != nil {
+		return fmt.Errorf("vNetClient.CreateOrUpdate failed for %q: %s", a.cfg.Network, err)
+	}
+	if err := a.waitForCompletion(ctx, &vNetFuture, a.waitCfg); err != nil {
+		return fmt.Errorf("vNetClient.CreateOrUpdate did not complete for %q: %s", a.cfg.Network, err)
+	}
+	return nil
+}
+
+// ensureSubnet returns the configured subnet, creating it first if it
+// doesn't already exist.
+func (a *AzureInstanceSet) ensureSubnet(ctx context.Context) (*network.Subnet, error) {
+	existing, err := a.subnetClient.Get(ctx, a.cfg.ResourceGroup, a.cfg.Network, a.cfg.Subnet, "")
+	switch {
+	case err == nil:
+		return &existing, nil
+	case !isNotFound(err):
+		return nil, fmt.Errorf("subnetClient.Get failed for %q: %s", a.cfg.Subnet, err)
+	}
+
+	subnet := network.Subnet{
+		SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+			AddressPrefix: to.StringPtr("10.0.0.0/24"),
+		},
+	}
+	subnetFuture, err := a.subnetClient.CreateOrUpdate(ctx, a.cfg.ResourceGroup, a.cfg.Network, a.cfg.Subnet, subnet)
+	if err != nil {
+		return nil, fmt.Errorf("subnetClient.CreateOrUpdate failed for %q: %s", a.cfg.Subnet, err)
+	}
+	if err := a.waitForCompletion(ctx, &subnetFuture, a.waitCfg); err != nil {
+		return nil, fmt.Errorf("subnetClient.CreateOrUpdate did not complete for %q: %s", a.cfg.Subnet, err)
+	}
+
+	subnetInfo, err := a.subnetClient.Get(ctx, a.cfg.ResourceGroup, a.cfg.Network, a.cfg.Subnet, "")
+	if err != nil {
+		return nil, fmt.Errorf("subnetClient.Get failed for subnet %q: %s", a.cfg.Subnet, err)
+	}
+	return &subnetInfo, nil
+}
+
+// ensureStorageAccount reuses the named storage account if it already
+// exists in the resource group. Otherwise it preflights name with
+// CheckNameAvailability, retrying with a randomized suffix on collision,
+// and creates the account under the first available name. It returns
+// the name the account was actually created or found under.
+func (a *AzureInstanceSet) ensureStorageAccount(ctx context.Context, name string) (string, error) {
+	if _, err := a.accountClient.GetProperties(ctx, a.cfg.ResourceGroup, name, ""); err == nil {
+		return name, nil
+	} else if !isNotFound(err) {
+		return "", fmt.Errorf("accountClient.GetProperties failed for %q: %s", name, err)
+	}
+
+	candidate := name
+	for attempt := 0; ; attempt++ {
+		result, err := a.accountClient.CheckNameAvailability(ctx, storage.AccountCheckNameAvailabilityParameters{
+			Name: to.StringPtr(candidate),
+			Type: to.StringPtr("Microsoft.Storage/storageAccounts"),
+		})
+		if err != nil {
+			return "", fmt.Errorf("accountClient.CheckNameAvailability failed for %q: %s", candidate, err)
+		}
+		if result.NameAvailable != nil && *result.NameAvailable {
+			break
+		}
+		if attempt >= maxNameCollisionRetries {
+			return "", fmt.Errorf("no available storage account name found after %d attempts, last tried %q", attempt+1, candidate)
+		}
+		suffix, err := randomSuffix(5)
+		if err != nil {
+			return "", err
+		}
+		candidate = name + suffix
+	}
+
+	accountParameters := storage.AccountCreateParameters{
+		Sku: &storage.Sku{
+			Name: storage.StandardLRS,
+		},
+		Location:                          &a.cfg.Location,
+		AccountPropertiesCreateParameters: &storage.AccountPropertiesCreateParameters{},
+	}
+	_, errChan := a.accountClient.Create(a.cfg.ResourceGroup, candidate, accountParameters, nil)
+	if err := <-errChan; err != nil {
+		return "", fmt.Errorf("accountClient.Create failed for storage account %q: %s", candidate, err)
+	}
+	return candidate, nil
+}
+
+// reuseOrEnsureDomainNameLabel returns the DNS label of the public IP
+// address named ipName if it already exists, so that re-running Create
+// against an existing VM reuses its previous label. Otherwise it
+// preflights requested with CheckDNSNameAvailability, retrying with a
+// randomized suffix on collision.
+func (a *AzureInstanceSet) reuseOrEnsureDomainNameLabel(ctx context.Context, ipName, requested string) (string, error) {
+	existing, err := a.addressClient.Get(ctx, a.cfg.ResourceGroup, ipName, "")
+	switch {
+	case err == nil:
+		if existing.DNSSettings != nil && existing.DNSSettings.DomainNameLabel != nil {
+			return *existing.DNSSettings.DomainNameLabel, nil
+		}
+	case !isNotFound(err):
+		return "", fmt.Errorf("addressClient.Get failed for %q: %s", ipName, err)
+	}
+	return a.ensureDomainNameLabel(ctx, requested)
+}
+
+// ensureDomainNameLabel finds a DNS label starting with requested that
+// CheckDNSNameAvailability reports as free, appending a randomized
+// suffix and retrying on collision.
+func (a *AzureInstanceSet) ensureDomainNameLabel(ctx context.Context, requested string) (string, error) {
+	candidate := requested
+	for attempt := 0; ; attempt++ {
+		result, err := a.dnsCheckClient.Get(ctx, a.cfg.Location, candidate)
+		if err != nil {
+			return "", fmt.Errorf("dnsCheckClient.Get failed for %q: %s", candidate, err)
+		}
+		if result.Available != nil && *result.Available {
+			return candidate, nil
+		}
+		if attempt >= maxNameCollisionRetries {
+			return "", fmt.Errorf("no available DNS label found after %d attempts, last tried %q", attempt+1, candidate)
+		}
+		suffix, err := randomSuffix(5)
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s-%s", requested, suffix)
+	}
+}
+
+// isNotFound reports whether err is an autorest error carrying a 404
+// status, e.g. from a Get against a resource that doesn't exist yet.
+func isNotFound(err error) bool {
+	var detailed autorest.DetailedError
+	if errors.As(err, &detailed) {
+		if code, ok := detailed.StatusCode.(int); ok {
+			return code == http.StatusNotFound
+		}
+	}
+	return false
+}
+
+// randomSuffix returns a random lowercase alphanumeric string of length
+// n, used to work around naming collisions on globally-unique Azure
+// resource names.
+func randomSuffix(n int) (string, error) {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, n)
+	if _, err := cryptorand.Read(b); err != nil {
+		return "", fmt.Errorf("generating random suffix failed: %s", err)
+	}
+	for i, c := range b {
+		b[i] = alphabet[int(c)%len(alphabet)]
+	}
+	return string(b), nil
+}
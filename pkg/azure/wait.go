@@ -0,0 +1,139 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// WaitConfig controls how waitForCompletion polls an ARM long-running
+// operation to completion.
+type WaitConfig struct {
+	// PollInterval is the delay between polls, and the starting point for
+	// the exponential backoff applied between retried waits. Defaults to
+	// 10s when zero.
+	PollInterval time.Duration
+
+	// MaxPollInterval caps the backoff between retried waits. Defaults to
+	// 1m when zero.
+	MaxPollInterval time.Duration
+}
+
+func (c WaitConfig) withDefaults() WaitConfig {
+	if c.PollInterval <= 0 {
+		c.PollInterval = 10 * time.Second
+	}
+	if c.MaxPollInterval <= 0 {
+		c.MaxPollInterval = time.Minute
+	}
+	return c
+}
+
+// OpErrorKind classifies why a long-running Azure operation did not
+// complete successfully.
+type OpErrorKind int
+
+const (
+	// OpErrorUnknown is any failure that doesn't fit the categories below.
+	OpErrorUnknown OpErrorKind = iota
+	// OpErrorThrottled means the operation was rejected with 429 or 5xx.
+	OpErrorThrottled
+	// OpErrorNotFound means the target resource was gone.
+	OpErrorNotFound
+	// OpErrorConflict means another operation is already running against the resource.
+	OpErrorConflict
+	// OpErrorProvisioningFailed means ARM accepted the operation but the
+	// resource ended up in a Failed provisioning state.
+	OpErrorProvisioningFailed
+)
+
+// OpError wraps a failed long-running operation with an OpErrorKind, so
+// callers can tell retryable failures (throttled, conflict) apart from
+// terminal ones (not found, provisioning failed).
+type OpError struct {
+	Kind OpErrorKind
+	Err  error
+}
+
+func (e *OpError) Error() string { return e.Err.Error() }
+func (e *OpError) Unwrap() error { return e.Err }
+
+// maxWaitRetries bounds how many times waitForCompletion retries a wait
+// that failed with a throttled (429/5xx) error.
+const maxWaitRetries = 5
+
+// future is implemented by every *Future type the compute, network and
+// storage SDK clients in this package return from their mutating calls.
+type future interface {
+	WaitForCompletionRef(ctx context.Context, client autorest.Client) error
+}
+
+// waitForCompletion blocks until f completes, ctx is cancelled, or
+// retries against a transient failure are exhausted. Each attempt polls
+// ARM every cfg.PollInterval via f.WaitForCompletionRef, whose HTTP
+// client itself retries transient errors through the autorest retry
+// decorator; an attempt that still fails with a throttled error is
+// retried with exponential backoff up to cfg.MaxPollInterval.
+func (a *AzureInstanceSet) waitForCompletion(ctx context.Context, f future, cfg WaitConfig) error {
+	cfg = cfg.withDefaults()
+
+	client := autorest.NewClientWithUserAgent("virtual-machines-go-manage")
+	client.PollingDelay = cfg.PollInterval
+	client.RetryAttempts = autorest.DefaultRetryAttempts
+	client.RetryDuration = cfg.PollInterval
+	client.Sender = autorest.DecorateSender(client.Sender,
+		autorest.DoRetryForStatusCodes(client.RetryAttempts, client.RetryDuration, autorest.StatusCodesForRetry...))
+
+	delay := cfg.PollInterval
+	for attempt := 0; ; attempt++ {
+		err := f.WaitForCompletionRef(ctx, client)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return &OpError{Kind: OpErrorUnknown, Err: ctx.Err()}
+		}
+
+		kind := classify(err)
+		if kind != OpErrorThrottled || attempt >= maxWaitRetries {
+			return &OpError{Kind: kind, Err: err}
+		}
+
+		select {
+		case <-ctx.Done():
+			return &OpError{Kind: OpErrorUnknown, Err: ctx.Err()}
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > cfg.MaxPollInterval {
+			delay = cfg.MaxPollInterval
+		}
+	}
+}
+
+// classify maps a failed wait into an OpErrorKind using the HTTP status
+// code on the underlying autorest error when available, falling back to
+// matching well-known ARM provisioning-failure text.
+func classify(err error) OpErrorKind {
+	var detailed autorest.DetailedError
+	if errors.As(err, &detailed) {
+		if code, ok := detailed.StatusCode.(int); ok {
+			switch {
+			case code == http.StatusTooManyRequests, code >= 500:
+				return OpErrorThrottled
+			case code == http.StatusNotFound:
+				return OpErrorNotFound
+			case code == http.StatusConflict:
+				return OpErrorConflict
+			}
+		}
+	}
+	if strings.Contains(err.Error(), "ProvisioningState/failed") || strings.Contains(err.Error(), "ProvisioningState 'Failed'") {
+		return OpErrorProvisioningFailed
+	}
+	return OpErrorUnknown
+}